@@ -1,6 +1,9 @@
 package stateswitch
 
-import "fmt"
+import (
+	"fmt"
+	"time"
+)
 
 // State is a unique identifier for a single state in a state machine, e.g.
 // "pending" or "done".
@@ -61,6 +64,17 @@ type StateMachine interface {
 	// whose SourceStates contains sw's current state and whose Condition (if
 	// any) passes, moving sw to the rule's DestinationState.
 	Run(transitionType TransitionType, sw StateSwitch, args TransitionArgs) error
+
+	// Serial returns the state machine's monotonic revision counter.
+	Serial() int64
+
+	// SerialAt reports whether the state machine's definition is unchanged
+	// since snapshot was generated.
+	SerialAt(snapshot StateMachineJSON) bool
+
+	// DiffSince reports what changed in the state machine's definition since
+	// prev was generated.
+	DiffSince(prev StateMachineJSON) (StateMachineDiff, error)
 }
 
 // stateMachine is the default StateMachine implementation.
@@ -68,6 +82,8 @@ type stateMachine struct {
 	transitionRules    map[TransitionType][]TransitionRule
 	stateDocs          map[State]StateDoc
 	transitionTypeDocs map[TransitionType]TransitionTypeDoc
+	serial             int64
+	generatedAt        time.Time
 }
 
 // NewStateMachine creates an empty StateMachine, ready for AddTransition
@@ -81,7 +97,9 @@ func NewStateMachine() StateMachine {
 	return sm
 }
 
-// AddTransition registers rule, keyed by its TransitionType.
+// AddTransition registers rule, keyed by its TransitionType. It mutates the
+// state machine's definition, so it bumps Serial the same way DescribeState
+// and DescribeTransitionType do.
 func (sm *stateMachine) AddTransition(rule TransitionRule) error {
 	for _, existing := range sm.transitionRules[rule.TransitionType] {
 		if statesListEqual(existing.SourceStates, rule.SourceStates) {
@@ -90,6 +108,7 @@ func (sm *stateMachine) AddTransition(rule TransitionRule) error {
 	}
 
 	sm.transitionRules[rule.TransitionType] = append(sm.transitionRules[rule.TransitionType], rule)
+	sm.bumpSerial()
 
 	return nil
 }
@@ -0,0 +1,286 @@
+package stateswitch
+
+import (
+	"bytes"
+	"fmt"
+	"regexp"
+	"sort"
+	"strings"
+)
+
+// initialStateID is the synthetic node used for transition rules whose only
+// source state is the empty string, i.e. the entry point of the state
+// machine. See initStateMachineDocumentation.
+const initialStateID = "initial"
+
+// DiagramOption customizes the output of AsDOT and AsMermaid.
+type DiagramOption func(*diagramOptions)
+
+type diagramOptions struct {
+	groupByTransitionType bool
+	hideDescriptions      bool
+	leftToRight           bool
+}
+
+// WithGroupByTransitionType colors each edge by its TransitionType, so that
+// transitions of the same kind are visually grouped in the rendered diagram.
+func WithGroupByTransitionType() DiagramOption {
+	return func(o *diagramOptions) {
+		o.groupByTransitionType = true
+	}
+}
+
+// WithoutDescriptions omits transition rule descriptions from edge labels,
+// leaving only the transition type and rule name.
+func WithoutDescriptions() DiagramOption {
+	return func(o *diagramOptions) {
+		o.hideDescriptions = true
+	}
+}
+
+// WithLeftToRight lays the diagram out left-to-right instead of the default
+// top-to-bottom.
+func WithLeftToRight() DiagramOption {
+	return func(o *diagramOptions) {
+		o.leftToRight = true
+	}
+}
+
+func newDiagramOptions(opts []DiagramOption) diagramOptions {
+	var options diagramOptions
+	for _, opt := range opts {
+		opt(&options)
+	}
+
+	return options
+}
+
+// diagramEdge is a single source -> destination transition, already resolved
+// to display names, ready to be rendered by AsDOT or AsMermaid.
+type diagramEdge struct {
+	from        string
+	to          string
+	transition  TransitionType
+	name        string
+	description string
+}
+
+func (e diagramEdge) label(hideDescription bool) string {
+	label := string(e.transition)
+	if e.name != "" {
+		label = fmt.Sprintf("%s: %s", e.transition, e.name)
+	}
+	if !hideDescription && e.description != "" {
+		label = fmt.Sprintf("%s (%s)", label, e.description)
+	}
+
+	return label
+}
+
+// diagramEdges walks the same sorted transitionRules that Export uses, so
+// that AsDOT/AsMermaid and AsJSON always agree on ordering.
+func (sm *stateMachine) diagramEdges() []diagramEdge {
+	keys := make([]TransitionType, 0, len(sm.transitionRules))
+	for tt := range sm.transitionRules {
+		keys = append(keys, tt)
+	}
+	sort.Slice(keys, func(i, j int) bool {
+		return string(keys[i]) < string(keys[j])
+	})
+
+	var edges []diagramEdge
+	for _, transition := range keys {
+		for _, rule := range sm.transitionRules[transition] {
+			sourceStates := rule.SourceStates
+			if len(sourceStates) == 1 && sourceStates[0] == State("") {
+				sourceStates = []State{State(initialStateID)}
+			}
+
+			for _, source := range sourceStates {
+				edges = append(edges, diagramEdge{
+					from:        sm.nodeLabel(source),
+					to:          sm.nodeLabel(rule.DestinationState),
+					transition:  transition,
+					name:        rule.Documentation.Name,
+					description: rule.Documentation.Description,
+				})
+			}
+		}
+	}
+
+	return edges
+}
+
+// nodeLabel returns the StateDoc.Name for state if one was registered via
+// DescribeState, falling back to the raw state id.
+func (sm *stateMachine) nodeLabel(state State) string {
+	if string(state) == initialStateID {
+		return initialStateID
+	}
+	if doc, ok := sm.stateDocs[state]; ok && doc.Name != "" {
+		return doc.Name
+	}
+
+	return string(state)
+}
+
+// transitionTypeColors assigns a stable color to each transition type found
+// in edges, used by WithGroupByTransitionType. Colors are taken from
+// Graphviz's built-in "set19" palette so they render without extra setup.
+func transitionTypeColors(edges []diagramEdge) map[TransitionType]string {
+	palette := []string{
+		"#e41a1c", "#377eb8", "#4daf4a", "#984ea3",
+		"#ff7f00", "#ffff33", "#a65628", "#f781bf", "#999999",
+	}
+
+	seen := make(map[TransitionType]bool)
+	var types []TransitionType
+	for _, edge := range edges {
+		if !seen[edge.transition] {
+			seen[edge.transition] = true
+			types = append(types, edge.transition)
+		}
+	}
+	sort.Slice(types, func(i, j int) bool {
+		return string(types[i]) < string(types[j])
+	})
+
+	colors := make(map[TransitionType]string, len(types))
+	for i, tt := range types {
+		colors[tt] = palette[i%len(palette)]
+	}
+
+	return colors
+}
+
+// AsDOT renders the state machine as a Graphviz DOT digraph. The synthetic
+// initial state is rendered as a point node; every other node uses the
+// StateDoc.Name registered via DescribeState, falling back to the raw state
+// id. Edges are labeled with the TransitionType and the TransitionRuleDoc.Name.
+func (sm *stateMachine) AsDOT(opts ...DiagramOption) ([]byte, error) {
+	options := newDiagramOptions(opts)
+	edges := sm.diagramEdges()
+
+	var buf bytes.Buffer
+	buf.WriteString("digraph stateswitch {\n")
+	if options.leftToRight {
+		buf.WriteString("  rankdir=LR;\n")
+	}
+	buf.WriteString(fmt.Sprintf("  %s [shape=point];\n", dotQuote(initialStateID)))
+
+	var colors map[TransitionType]string
+	if options.groupByTransitionType {
+		colors = transitionTypeColors(edges)
+	}
+
+	for _, edge := range edges {
+		attrs := fmt.Sprintf("label=%s", dotQuote(edge.label(options.hideDescriptions)))
+		if color, ok := colors[edge.transition]; ok {
+			attrs += fmt.Sprintf(", color=%s, fontcolor=%s", dotQuote(color), dotQuote(color))
+		}
+		buf.WriteString(fmt.Sprintf("  %s -> %s [%s];\n", dotQuote(edge.from), dotQuote(edge.to), attrs))
+	}
+
+	buf.WriteString("}\n")
+
+	return buf.Bytes(), nil
+}
+
+// AsMermaid renders the state machine as a Mermaid stateDiagram-v2. The
+// synthetic initial state is rendered as the Mermaid entry marker [*]; every
+// other node is declared with a sanitized alias and a `state "Name" as alias`
+// line, so node names containing spaces, colons, or other characters Mermaid
+// treats specially still render correctly.
+//
+// WithGroupByTransitionType is a DOT-only option: Mermaid state diagrams have
+// no equivalent way to color individual edges, so AsMermaid rejects it
+// rather than silently ignoring it.
+func (sm *stateMachine) AsMermaid(opts ...DiagramOption) ([]byte, error) {
+	options := newDiagramOptions(opts)
+	if options.groupByTransitionType {
+		return nil, fmt.Errorf("WithGroupByTransitionType is a DOT-only option and is not supported by AsMermaid")
+	}
+
+	edges := sm.diagramEdges()
+	aliases := mermaidAliases(edges)
+
+	var buf bytes.Buffer
+	buf.WriteString("stateDiagram-v2\n")
+	if options.leftToRight {
+		buf.WriteString("  direction LR\n")
+	}
+
+	names := make([]string, 0, len(aliases))
+	for name := range aliases {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		buf.WriteString(fmt.Sprintf("  state %q as %s\n", name, aliases[name]))
+	}
+
+	for _, edge := range edges {
+		from := mermaidNode(edge.from, aliases)
+		to := mermaidNode(edge.to, aliases)
+		buf.WriteString(fmt.Sprintf("  %s --> %s: %s\n", from, to, edge.label(options.hideDescriptions)))
+	}
+
+	return buf.Bytes(), nil
+}
+
+func dotQuote(s string) string {
+	return fmt.Sprintf("%q", s)
+}
+
+// mermaidAliasPattern matches runs of characters that aren't safe to use
+// unquoted in a Mermaid node identifier.
+var mermaidAliasPattern = regexp.MustCompile(`[^A-Za-z0-9_]+`)
+
+// mermaidAliases assigns every non-initial node referenced by edges a stable,
+// collision-free Mermaid identifier derived from its display name.
+func mermaidAliases(edges []diagramEdge) map[string]string {
+	seen := make(map[string]bool)
+	var names []string
+	addName := func(name string) {
+		if name == initialStateID || seen[name] {
+			return
+		}
+		seen[name] = true
+		names = append(names, name)
+	}
+	for _, edge := range edges {
+		addName(edge.from)
+		addName(edge.to)
+	}
+	sort.Strings(names)
+
+	aliases := make(map[string]string, len(names))
+	used := make(map[string]bool, len(names))
+	for _, name := range names {
+		base := mermaidAliasPattern.ReplaceAllString(name, "_")
+		base = strings.Trim(base, "_")
+		if base == "" {
+			base = "n"
+		}
+		if base[0] >= '0' && base[0] <= '9' {
+			base = "n_" + base
+		}
+
+		alias := base
+		for i := 2; used[alias]; i++ {
+			alias = fmt.Sprintf("%s_%d", base, i)
+		}
+		used[alias] = true
+		aliases[name] = alias
+	}
+
+	return aliases
+}
+
+func mermaidNode(name string, aliases map[string]string) string {
+	if name == initialStateID {
+		return "[*]"
+	}
+
+	return aliases[name]
+}
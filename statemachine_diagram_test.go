@@ -0,0 +1,236 @@
+package stateswitch
+
+import (
+	"strings"
+	"testing"
+)
+
+func buildDiagramFixture(t *testing.T) StateMachine {
+	t.Helper()
+
+	sm := NewStateMachine()
+	sm.DescribeState(State("open"), StateDoc{Name: "Open"})
+	sm.DescribeState(State("closed"), StateDoc{Name: "Closed"})
+	sm.DescribeTransitionType(TransitionType("create"), TransitionTypeDoc{Name: "Create"})
+	sm.DescribeTransitionType(TransitionType("reopen"), TransitionTypeDoc{Name: "Reopen"})
+
+	if err := sm.AddTransition(TransitionRule{
+		TransitionType:   "create",
+		SourceStates:     StatesList{""},
+		DestinationState: "open",
+		Documentation:    TransitionRuleDoc{Name: "Create", Description: "opens a new item"},
+	}); err != nil {
+		t.Fatalf("failed to add 'create' transition: %v", err)
+	}
+	if err := sm.AddTransition(TransitionRule{
+		TransitionType:   "reopen",
+		SourceStates:     StatesList{"open", "closed"},
+		DestinationState: "open",
+		Documentation:    TransitionRuleDoc{Name: "Reopen"},
+	}); err != nil {
+		t.Fatalf("failed to add 'reopen' transition: %v", err)
+	}
+
+	return sm
+}
+
+func TestAsDOTRendersInitialAndMultiSourceFanOut(t *testing.T) {
+	sm := buildDiagramFixture(t)
+
+	dot, err := sm.AsDOT()
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	out := string(dot)
+
+	if !strings.HasPrefix(out, "digraph stateswitch {") {
+		t.Errorf("expected a digraph header, got: %s", out)
+	}
+	if !strings.Contains(out, `"initial" [shape=point];`) {
+		t.Errorf("expected the initial state to render as a point node, got: %s", out)
+	}
+	if !strings.Contains(out, `"initial" -> "Open"`) {
+		t.Errorf("expected an edge from the initial node to 'Open', got: %s", out)
+	}
+	// reopen has two source states, so it must fan out into two edges.
+	if !strings.Contains(out, `"Open" -> "Open"`) || !strings.Contains(out, `"Closed" -> "Open"`) {
+		t.Errorf("expected one edge per source state of the 'reopen' rule, got: %s", out)
+	}
+}
+
+func TestAsDOTOptions(t *testing.T) {
+	sm := buildDiagramFixture(t)
+
+	withDescriptions, err := sm.AsDOT()
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	if !strings.Contains(string(withDescriptions), "opens a new item") {
+		t.Errorf("expected the description to appear by default, got: %s", withDescriptions)
+	}
+
+	withoutDescriptions, err := sm.AsDOT(WithoutDescriptions())
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	if strings.Contains(string(withoutDescriptions), "opens a new item") {
+		t.Errorf("expected WithoutDescriptions to omit the description, got: %s", withoutDescriptions)
+	}
+
+	grouped, err := sm.AsDOT(WithGroupByTransitionType())
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	if !strings.Contains(string(grouped), "color=") {
+		t.Errorf("expected WithGroupByTransitionType to color edges, got: %s", grouped)
+	}
+
+	lr, err := sm.AsDOT(WithLeftToRight())
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	if !strings.Contains(string(lr), "rankdir=LR;") {
+		t.Errorf("expected WithLeftToRight to set rankdir=LR, got: %s", lr)
+	}
+}
+
+func TestAsMermaidRendersEntryMarkerAndFanOut(t *testing.T) {
+	sm := buildDiagramFixture(t)
+
+	mermaid, err := sm.AsMermaid()
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	out := string(mermaid)
+
+	if !strings.HasPrefix(out, "stateDiagram-v2\n") {
+		t.Errorf("expected a stateDiagram-v2 header, got: %s", out)
+	}
+	if !strings.Contains(out, "[*] --> Open") {
+		t.Errorf("expected the initial state to render as [*], got: %s", out)
+	}
+	if !strings.Contains(out, "Open --> Open") || !strings.Contains(out, "Closed --> Open") {
+		t.Errorf("expected one edge per source state of the 'reopen' rule, got: %s", out)
+	}
+
+	lr, err := sm.AsMermaid(WithLeftToRight())
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	if !strings.Contains(string(lr), "direction LR") {
+		t.Errorf("expected WithLeftToRight to set direction LR, got: %s", lr)
+	}
+}
+
+func TestDiagramEdgeLabel(t *testing.T) {
+	edge := diagramEdge{
+		transition:  "submit",
+		name:        "Submit",
+		description: "user submits the form",
+	}
+
+	if got, want := edge.label(false), "submit: Submit (user submits the form)"; got != want {
+		t.Errorf("label(false) = %q, want %q", got, want)
+	}
+	if got, want := edge.label(true), "submit: Submit"; got != want {
+		t.Errorf("label(true) = %q, want %q", got, want)
+	}
+}
+
+func TestDiagramEdgeLabelWithoutName(t *testing.T) {
+	edge := diagramEdge{transition: "submit"}
+
+	if got, want := edge.label(false), "submit"; got != want {
+		t.Errorf("label(false) = %q, want %q", got, want)
+	}
+}
+
+func TestTransitionTypeColorsIsStable(t *testing.T) {
+	edges := []diagramEdge{
+		{transition: "b"},
+		{transition: "a"},
+		{transition: "b"},
+	}
+
+	first := transitionTypeColors(edges)
+	second := transitionTypeColors(edges)
+
+	if len(first) != 2 {
+		t.Fatalf("expected 2 colors, got %d", len(first))
+	}
+	if first["a"] != second["a"] || first["b"] != second["b"] {
+		t.Errorf("expected deterministic colors across calls, got %v then %v", first, second)
+	}
+	if first["a"] == first["b"] {
+		t.Errorf("expected distinct colors for distinct transition types, both got %q", first["a"])
+	}
+}
+
+func TestMermaidNode(t *testing.T) {
+	aliases := map[string]string{"Done": "Done"}
+
+	if got, want := mermaidNode(initialStateID, aliases), "[*]"; got != want {
+		t.Errorf("mermaidNode(initial) = %q, want %q", got, want)
+	}
+	if got, want := mermaidNode("Done", aliases), "Done"; got != want {
+		t.Errorf("mermaidNode(Done) = %q, want %q", got, want)
+	}
+}
+
+func TestMermaidAliasesSanitizeSpacesAndColons(t *testing.T) {
+	edges := []diagramEdge{
+		{from: initialStateID, to: "Open For Business"},
+		{from: "Open For Business", to: "Closed: Archived"},
+	}
+
+	aliases := mermaidAliases(edges)
+
+	if _, ok := aliases[initialStateID]; ok {
+		t.Errorf("expected the initial state to be excluded from aliases, got %v", aliases)
+	}
+	for name, alias := range aliases {
+		if strings.ContainsAny(alias, " :") {
+			t.Errorf("alias %q for %q still contains unsafe characters", alias, name)
+		}
+	}
+	if aliases["Open For Business"] == aliases["Closed: Archived"] {
+		t.Errorf("expected distinct aliases for distinct names, both got %q", aliases["Open For Business"])
+	}
+}
+
+func TestAsMermaidQuotesMultiWordStateNames(t *testing.T) {
+	sm := NewStateMachine()
+	sm.DescribeState(State("open"), StateDoc{Name: "Open For Business"})
+	sm.DescribeState(State("closed"), StateDoc{Name: "Closed: Archived"})
+	sm.DescribeTransitionType(TransitionType("create"), TransitionTypeDoc{Name: "Create"})
+
+	if err := sm.AddTransition(TransitionRule{
+		TransitionType:   "create",
+		SourceStates:     StatesList{""},
+		DestinationState: "open",
+		Documentation:    TransitionRuleDoc{Name: "Create"},
+	}); err != nil {
+		t.Fatalf("failed to add 'create' transition: %v", err)
+	}
+
+	mermaid, err := sm.AsMermaid()
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	out := string(mermaid)
+
+	if !strings.Contains(out, `state "Open For Business" as `) {
+		t.Errorf("expected an aliased state declaration for 'Open For Business', got: %s", out)
+	}
+	if strings.Contains(out, "[*] --> Open For Business") {
+		t.Errorf("expected the raw multi-word name to be replaced by its alias in the edge, got: %s", out)
+	}
+}
+
+func TestAsMermaidRejectsGroupByTransitionType(t *testing.T) {
+	sm := buildDiagramFixture(t)
+
+	if _, err := sm.AsMermaid(WithGroupByTransitionType()); err == nil {
+		t.Fatal("expected AsMermaid to reject WithGroupByTransitionType, got nil error")
+	}
+}
@@ -0,0 +1,238 @@
+package stateswitch
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// ModifiedTransitionRule describes a transition rule whose key (transition
+// type, source states, destination state) is unchanged between two
+// StateMachineJSON documents, but whose documentation changed.
+type ModifiedTransitionRule struct {
+	TransitionType   TransitionType `json:"transition_type"`
+	SourceStates     []string       `json:"source_states"`
+	DestinationState string         `json:"destination_state"`
+
+	OldName        string `json:"old_name,omitempty"`
+	NewName        string `json:"new_name,omitempty"`
+	OldDescription string `json:"old_description,omitempty"`
+	NewDescription string `json:"new_description,omitempty"`
+}
+
+// StateMachineDiff is the structural difference between two
+// StateMachineJSON documents, as computed by Diff.
+type StateMachineDiff struct {
+	AddedStates   []string `json:"added_states,omitempty"`
+	RemovedStates []string `json:"removed_states,omitempty"`
+
+	AddedTransitionTypes   []string `json:"added_transition_types,omitempty"`
+	RemovedTransitionTypes []string `json:"removed_transition_types,omitempty"`
+
+	AddedRules    []TransitionRuleJSON     `json:"added_rules,omitempty"`
+	RemovedRules  []TransitionRuleJSON     `json:"removed_rules,omitempty"`
+	ModifiedRules []ModifiedTransitionRule `json:"modified_rules,omitempty"`
+}
+
+// Empty reports whether the diff found no differences at all.
+func (d StateMachineDiff) Empty() bool {
+	return len(d.AddedStates) == 0 && len(d.RemovedStates) == 0 &&
+		len(d.AddedTransitionTypes) == 0 && len(d.RemovedTransitionTypes) == 0 &&
+		len(d.AddedRules) == 0 && len(d.RemovedRules) == 0 && len(d.ModifiedRules) == 0
+}
+
+// Summary returns a short count of each kind of change, e.g.
+// "2 states added, 1 rule removed, 1 rule modified".
+func (d StateMachineDiff) Summary() string {
+	if d.Empty() {
+		return "no changes"
+	}
+
+	var parts []string
+	addCount := func(n int, noun string) {
+		if n == 0 {
+			return
+		}
+		plural := noun + "s"
+		if n == 1 {
+			plural = noun
+		}
+		parts = append(parts, fmt.Sprintf("%d %s", n, plural))
+	}
+
+	addCount(len(d.AddedStates), "state added")
+	addCount(len(d.RemovedStates), "state removed")
+	addCount(len(d.AddedTransitionTypes), "transition type added")
+	addCount(len(d.RemovedTransitionTypes), "transition type removed")
+	addCount(len(d.AddedRules), "rule added")
+	addCount(len(d.RemovedRules), "rule removed")
+	addCount(len(d.ModifiedRules), "rule modified")
+
+	return strings.Join(parts, ", ")
+}
+
+// AsJSON renders the diff as indented JSON.
+func (d StateMachineDiff) AsJSON() ([]byte, error) {
+	marshaled, err := json.MarshalIndent(d, "", "  ")
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal state machine diff to JSON: %w", err)
+	}
+
+	return marshaled, nil
+}
+
+// AsMarkdown renders the diff as a short Markdown summary, suitable for
+// posting as a PR comment.
+func (d StateMachineDiff) AsMarkdown() string {
+	var buf bytes.Buffer
+
+	fmt.Fprintf(&buf, "**State machine diff**: %s\n", d.Summary())
+	if d.Empty() {
+		return buf.String()
+	}
+
+	writeList := func(title string, items []string) {
+		if len(items) == 0 {
+			return
+		}
+		fmt.Fprintf(&buf, "\n%s:\n", title)
+		for _, item := range items {
+			fmt.Fprintf(&buf, "- `%s`\n", item)
+		}
+	}
+
+	writeList("Added states", d.AddedStates)
+	writeList("Removed states", d.RemovedStates)
+	writeList("Added transition types", d.AddedTransitionTypes)
+	writeList("Removed transition types", d.RemovedTransitionTypes)
+
+	if len(d.AddedRules) > 0 {
+		fmt.Fprintf(&buf, "\nAdded rules:\n")
+		for _, rule := range d.AddedRules {
+			fmt.Fprintf(&buf, "- `%s`: %s -> %s\n", rule.TransitionType, strings.Join(rule.SourceStates, ", "), rule.DestinationState)
+		}
+	}
+	if len(d.RemovedRules) > 0 {
+		fmt.Fprintf(&buf, "\nRemoved rules:\n")
+		for _, rule := range d.RemovedRules {
+			fmt.Fprintf(&buf, "- `%s`: %s -> %s\n", rule.TransitionType, strings.Join(rule.SourceStates, ", "), rule.DestinationState)
+		}
+	}
+	if len(d.ModifiedRules) > 0 {
+		fmt.Fprintf(&buf, "\nModified rules:\n")
+		for _, rule := range d.ModifiedRules {
+			fmt.Fprintf(&buf, "- `%s`: %s -> %s (name %q -> %q)\n", rule.TransitionType, strings.Join(rule.SourceStates, ", "), rule.DestinationState, rule.OldName, rule.NewName)
+		}
+	}
+
+	return buf.String()
+}
+
+// Diff compares two StateMachineJSON documents and reports the states,
+// transition types, and transition rules added, removed, or (for rules)
+// modified between a and b. Rules are matched by TransitionType,
+// SourceStates, and DestinationState; a rule whose key is unchanged but
+// whose Name or Description differs is reported as modified rather than as
+// an add/remove pair. Output is sorted so that Diff is deterministic,
+// matching the ordering Export already uses.
+func Diff(a, b StateMachineJSON) StateMachineDiff {
+	var diff StateMachineDiff
+
+	diff.AddedStates = diffStateKeys(a.States, b.States)
+	diff.RemovedStates = diffStateKeys(b.States, a.States)
+	diff.AddedTransitionTypes = diffTransitionTypeKeys(a.TransitionTypes, b.TransitionTypes)
+	diff.RemovedTransitionTypes = diffTransitionTypeKeys(b.TransitionTypes, a.TransitionTypes)
+	diff.AddedRules, diff.RemovedRules, diff.ModifiedRules = diffRules(a.TransitionRules, b.TransitionRules)
+
+	return diff
+}
+
+func diffStateKeys(from, to map[string]StateJSON) []string {
+	var added []string
+	for id := range to {
+		if _, ok := from[id]; !ok {
+			added = append(added, id)
+		}
+	}
+	sort.Strings(added)
+
+	return added
+}
+
+func diffTransitionTypeKeys(from, to map[string]TransitionTypeJSON) []string {
+	var added []string
+	for id := range to {
+		if _, ok := from[id]; !ok {
+			added = append(added, id)
+		}
+	}
+	sort.Strings(added)
+
+	return added
+}
+
+// ruleKey identifies a transition rule for diffing purposes: its
+// TransitionType, SourceStates, and DestinationState. Two rules with the
+// same key but different Name/Description are a modification, not an
+// add/remove pair.
+func ruleKey(rule TransitionRuleJSON) string {
+	sources := append([]string(nil), rule.SourceStates...)
+	sort.Strings(sources)
+
+	return fmt.Sprintf("%s|%s|%s", rule.TransitionType, strings.Join(sources, ","), rule.DestinationState)
+}
+
+func diffRules(from, to []TransitionRuleJSON) (added, removed []TransitionRuleJSON, modified []ModifiedTransitionRule) {
+	fromByKey := make(map[string]TransitionRuleJSON, len(from))
+	for _, rule := range from {
+		fromByKey[ruleKey(rule)] = rule
+	}
+	toByKey := make(map[string]TransitionRuleJSON, len(to))
+	for _, rule := range to {
+		toByKey[ruleKey(rule)] = rule
+	}
+
+	var keys []string
+	seen := make(map[string]bool)
+	for _, rule := range from {
+		k := ruleKey(rule)
+		if !seen[k] {
+			seen[k] = true
+			keys = append(keys, k)
+		}
+	}
+	for _, rule := range to {
+		k := ruleKey(rule)
+		if !seen[k] {
+			seen[k] = true
+			keys = append(keys, k)
+		}
+	}
+	sort.Strings(keys)
+
+	for _, k := range keys {
+		oldRule, hadOld := fromByKey[k]
+		newRule, hasNew := toByKey[k]
+
+		switch {
+		case !hadOld:
+			added = append(added, newRule)
+		case !hasNew:
+			removed = append(removed, oldRule)
+		case oldRule.Name != newRule.Name || oldRule.Description != newRule.Description:
+			modified = append(modified, ModifiedTransitionRule{
+				TransitionType:   newRule.TransitionType,
+				SourceStates:     newRule.SourceStates,
+				DestinationState: newRule.DestinationState,
+				OldName:          oldRule.Name,
+				NewName:          newRule.Name,
+				OldDescription:   oldRule.Description,
+				NewDescription:   newRule.Description,
+			})
+		}
+	}
+
+	return added, removed, modified
+}
@@ -0,0 +1,87 @@
+package stateswitch
+
+import "testing"
+
+func TestDiffDetectsAddedAndRemovedStates(t *testing.T) {
+	a := StateMachineJSON{
+		States:          map[string]StateJSON{"initial": {}, "open": {}},
+		TransitionTypes: map[string]TransitionTypeJSON{"create": {}},
+	}
+	b := StateMachineJSON{
+		States:          map[string]StateJSON{"initial": {}, "closed": {}},
+		TransitionTypes: map[string]TransitionTypeJSON{"create": {}, "close": {}},
+	}
+
+	diff := Diff(a, b)
+
+	if len(diff.AddedStates) != 1 || diff.AddedStates[0] != "closed" {
+		t.Errorf("expected added state 'closed', got %v", diff.AddedStates)
+	}
+	if len(diff.RemovedStates) != 1 || diff.RemovedStates[0] != "open" {
+		t.Errorf("expected removed state 'open', got %v", diff.RemovedStates)
+	}
+	if len(diff.AddedTransitionTypes) != 1 || diff.AddedTransitionTypes[0] != "close" {
+		t.Errorf("expected added transition type 'close', got %v", diff.AddedTransitionTypes)
+	}
+}
+
+func TestDiffOfIdenticalDocumentsIsEmpty(t *testing.T) {
+	doc := StateMachineJSON{
+		States:          map[string]StateJSON{"initial": {}},
+		TransitionTypes: map[string]TransitionTypeJSON{"create": {}},
+	}
+
+	if diff := Diff(doc, doc); !diff.Empty() {
+		t.Errorf("expected an empty diff, got %+v", diff)
+	}
+	if summary := Diff(doc, doc).Summary(); summary != "no changes" {
+		t.Errorf("expected summary 'no changes', got %q", summary)
+	}
+}
+
+func TestDiffDetectsAddedRemovedAndModifiedRules(t *testing.T) {
+	a := StateMachineJSON{
+		TransitionRules: []TransitionRuleJSON{
+			{TransitionType: "create", SourceStates: []string{"initial"}, DestinationState: "open", Name: "Create"},
+			{TransitionType: "close", SourceStates: []string{"open"}, DestinationState: "closed"},
+		},
+	}
+	b := StateMachineJSON{
+		TransitionRules: []TransitionRuleJSON{
+			{TransitionType: "create", SourceStates: []string{"initial"}, DestinationState: "open", Name: "Open"},
+			{TransitionType: "reopen", SourceStates: []string{"closed"}, DestinationState: "open"},
+		},
+	}
+
+	diff := Diff(a, b)
+
+	if len(diff.RemovedRules) != 1 || diff.RemovedRules[0].TransitionType != "close" {
+		t.Errorf("expected 'close' rule to be removed, got %v", diff.RemovedRules)
+	}
+	if len(diff.AddedRules) != 1 || diff.AddedRules[0].TransitionType != "reopen" {
+		t.Errorf("expected 'reopen' rule to be added, got %v", diff.AddedRules)
+	}
+	if len(diff.ModifiedRules) != 1 || diff.ModifiedRules[0].OldName != "Create" || diff.ModifiedRules[0].NewName != "Open" {
+		t.Errorf("expected 'create' rule to be modified from Create to Open, got %v", diff.ModifiedRules)
+	}
+}
+
+func TestStateMachineDiffAsJSONAndMarkdown(t *testing.T) {
+	diff := Diff(
+		StateMachineJSON{States: map[string]StateJSON{}},
+		StateMachineJSON{States: map[string]StateJSON{"open": {}}},
+	)
+
+	data, err := diff.AsJSON()
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	if len(data) == 0 {
+		t.Error("expected non-empty JSON output")
+	}
+
+	markdown := diff.AsMarkdown()
+	if markdown == "" {
+		t.Error("expected non-empty Markdown output")
+	}
+}
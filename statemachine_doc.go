@@ -4,6 +4,7 @@ import (
 	"encoding/json"
 	"fmt"
 	"sort"
+	"time"
 )
 
 type StateMachineDocumentation interface {
@@ -23,6 +24,14 @@ type StateMachineDocumentation interface {
 	// diagram
 	AsJSON() ([]byte, error)
 
+	// AsDOT renders the state machine as a Graphviz DOT digraph, suitable for
+	// feeding to `dot -Tsvg`.
+	AsDOT(opts ...DiagramOption) ([]byte, error)
+
+	// AsMermaid renders the state machine as a Mermaid stateDiagram-v2,
+	// suitable for embedding in Markdown that Mermaid can render.
+	AsMermaid(opts ...DiagramOption) ([]byte, error)
+
 	Export() StateMachineJSON
 }
 
@@ -66,6 +75,21 @@ type TransitionRuleDoc struct {
 }
 
 type StateMachineJSON struct {
+	// FormatVersion is the schema version of this document, e.g. "1.0". See
+	// CurrentFormatVersion and ParseStateMachineJSON.
+	FormatVersion string `json:"format_version"`
+
+	// LibraryVersion is the semver of the stateswitch release that produced
+	// this document. See LibraryVersion.
+	LibraryVersion string `json:"stateswitch_version"`
+
+	// Serial is the state machine's revision counter at the time this
+	// document was generated. See stateMachine.Serial and SerialAt.
+	Serial int64 `json:"serial"`
+
+	// GeneratedAt is when this document was generated.
+	GeneratedAt time.Time `json:"generated_at"`
+
 	TransitionRuleNodes []TransitionRuleNode          `json:"transition_rules_nodes"`
 	TransitionRuleEdges []TransitionRuleEdge          `json:"transition_rules_edges"`
 	TransitionRules     []TransitionRuleJSON          `json:"transition_rules"`
@@ -110,10 +134,12 @@ type StateDocJSON struct {
 
 func (sm *stateMachine) DescribeState(state State, stateDocumentation StateDoc) {
 	sm.stateDocs[state] = stateDocumentation
+	sm.bumpSerial()
 }
 
 func (sm *stateMachine) DescribeTransitionType(transitionType TransitionType, transitionTypeDocumentation TransitionTypeDoc) {
 	sm.transitionTypeDocs[transitionType] = transitionTypeDocumentation
+	sm.bumpSerial()
 }
 
 func (sm *stateMachine) Export() StateMachineJSON {
@@ -127,7 +153,12 @@ func (sm *stateMachine) Export() StateMachineJSON {
 		return string(keys[i]) < string(keys[j])
 	})
 
-	stateMachineJSON := StateMachineJSON{}
+	stateMachineJSON := StateMachineJSON{
+		FormatVersion:  CurrentFormatVersion,
+		LibraryVersion: LibraryVersion,
+		Serial:         sm.serial,
+		GeneratedAt:    sm.generatedAt,
+	}
 	for _, transition := range keys {
 		for _, rule := range sm.transitionRules[transition] {
 			var sourceStates []string
@@ -0,0 +1,204 @@
+package stateswitch
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// ValidationWarning flags a non-fatal structural oddity found by Validate,
+// such as an unreachable state or a state with no outgoing rules. Terminal
+// states are a valid design, so these are reported rather than treated as
+// errors.
+type ValidationWarning struct {
+	State   string
+	Message string
+}
+
+// Validate checks that doc is internally consistent: every transition
+// rule's source and destination states must appear in States, and every
+// transition rule's TransitionType must appear in TransitionTypes. It does
+// not check for unreachable or dead-end states - those are not structural
+// errors, so they are opt-in via the separate Warnings method. Validate is
+// the check LoadStateMachineJSON runs before rebuilding a StateMachine;
+// callers who also want unreachable/dead-end diagnostics should call
+// Warnings themselves.
+func (doc StateMachineJSON) Validate() error {
+	var errs []string
+
+	for _, rule := range doc.TransitionRules {
+		if _, ok := doc.TransitionTypes[string(rule.TransitionType)]; !ok {
+			errs = append(errs, fmt.Sprintf("transition rule %q references unknown transition type %q", rule.Name, rule.TransitionType))
+		}
+
+		for _, source := range rule.SourceStates {
+			if _, ok := doc.States[source]; !ok {
+				errs = append(errs, fmt.Sprintf("transition rule %q references unknown source state %q", rule.Name, source))
+			}
+		}
+
+		if _, ok := doc.States[rule.DestinationState]; !ok {
+			errs = append(errs, fmt.Sprintf("transition rule %q references unknown destination state %q", rule.Name, rule.DestinationState))
+		}
+	}
+
+	if len(errs) > 0 {
+		sort.Strings(errs)
+		return fmt.Errorf("state machine JSON is invalid:\n  %s", strings.Join(errs, "\n  "))
+	}
+
+	return nil
+}
+
+// Warnings reports states that are unreachable from the synthetic "initial"
+// state, and states with no outgoing transition rules. A state with no
+// outgoing rules is a legitimate terminal sink, so it is a warning rather
+// than an error. Warnings is opt-in: LoadStateMachineJSON does not call it,
+// so a declarative state machine with dead-end or unreachable states loads
+// without complaint unless the caller checks doc.Warnings() too.
+func (doc StateMachineJSON) Warnings() []ValidationWarning {
+	outgoing := make(map[string]bool)
+	reachable := map[string]bool{initialStateID: true}
+
+	adjacency := make(map[string][]string)
+	for _, rule := range doc.TransitionRules {
+		for _, source := range rule.SourceStates {
+			outgoing[source] = true
+			adjacency[source] = append(adjacency[source], rule.DestinationState)
+		}
+	}
+
+	queue := []string{initialStateID}
+	for len(queue) > 0 {
+		state := queue[0]
+		queue = queue[1:]
+		for _, next := range adjacency[state] {
+			if !reachable[next] {
+				reachable[next] = true
+				queue = append(queue, next)
+			}
+		}
+	}
+
+	var stateIDs []string
+	for id := range doc.States {
+		stateIDs = append(stateIDs, id)
+	}
+	sort.Strings(stateIDs)
+
+	var warnings []ValidationWarning
+	for _, id := range stateIDs {
+		if !reachable[id] {
+			warnings = append(warnings, ValidationWarning{State: id, Message: "state is unreachable from the initial state"})
+		}
+		if !outgoing[id] {
+			warnings = append(warnings, ValidationWarning{State: id, Message: "state has no outgoing transition rules (terminal sink)"})
+		}
+	}
+
+	return warnings
+}
+
+// LoadStateMachineJSON reconstructs a runnable StateMachine from a document
+// produced by AsJSON/Export. It rejects the document if Validate finds it
+// structurally inconsistent, but does not consult Warnings - call
+// LoadStateMachineJSONWithWarnings instead if callers should see
+// unreachable/dead-end states by default. Every transition rule in the
+// document must have a corresponding entry in handlers, keyed by
+// transitionHandlerKey(rule's TransitionType, rule's Name): handlers are
+// registered separately from the document and wired in by name, so the same
+// JSON document can be paired with different handler implementations in
+// different services.
+func LoadStateMachineJSON(data []byte, handlers map[string]TransitionHandler) (StateMachine, error) {
+	doc, err := ParseStateMachineJSON(data)
+	if err != nil {
+		return nil, err
+	}
+
+	return buildStateMachineFromJSON(doc, handlers)
+}
+
+// LoadStateMachineJSONWithWarnings behaves like LoadStateMachineJSON, but
+// also returns doc.Warnings() alongside the rebuilt StateMachine, so
+// unreachable or dead-end states are surfaced by default instead of
+// requiring a separate Warnings call.
+func LoadStateMachineJSONWithWarnings(data []byte, handlers map[string]TransitionHandler) (StateMachine, []ValidationWarning, error) {
+	doc, err := ParseStateMachineJSON(data)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	sm, err := buildStateMachineFromJSON(doc, handlers)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	return sm, doc.Warnings(), nil
+}
+
+// buildStateMachineFromJSON validates doc and rebuilds a runnable
+// StateMachine from it, wiring each transition rule to the handler
+// registered under transitionHandlerKey(rule's TransitionType, rule's Name).
+func buildStateMachineFromJSON(doc StateMachineJSON, handlers map[string]TransitionHandler) (StateMachine, error) {
+	if err := doc.Validate(); err != nil {
+		return nil, err
+	}
+
+	sm := NewStateMachine()
+
+	for stateID, stateDoc := range doc.States {
+		sm.DescribeState(State(stateID), StateDoc{
+			Name:        stateDoc.Name,
+			Description: stateDoc.Description,
+		})
+	}
+
+	for ttID, ttDoc := range doc.TransitionTypes {
+		sm.DescribeTransitionType(TransitionType(ttID), TransitionTypeDoc{
+			Name:        ttDoc.Name,
+			Description: ttDoc.Description,
+		})
+	}
+
+	for _, rule := range doc.TransitionRules {
+		key := transitionHandlerKey(rule.TransitionType, rule.Name)
+		handler, ok := handlers[key]
+		if !ok {
+			return nil, fmt.Errorf("no handler registered for transition rule %q (key %q)", rule.Name, key)
+		}
+
+		sourceStates := make([]State, len(rule.SourceStates))
+		if len(rule.SourceStates) == 1 && rule.SourceStates[0] == initialStateID {
+			// Mirror Export: the synthetic "initial" source only stands in
+			// for the empty-string source state when it's the rule's single
+			// source. A real state literally named "initial" among several
+			// source states is left untouched.
+			sourceStates[0] = State("")
+		} else {
+			for i, source := range rule.SourceStates {
+				sourceStates[i] = State(source)
+			}
+		}
+
+		if err := sm.AddTransition(TransitionRule{
+			TransitionType:   rule.TransitionType,
+			SourceStates:     sourceStates,
+			DestinationState: State(rule.DestinationState),
+			Transition:       handler,
+			Documentation: TransitionRuleDoc{
+				Name:        rule.Name,
+				Description: rule.Description,
+			},
+		}); err != nil {
+			return nil, fmt.Errorf("failed to add transition rule %q: %w", rule.Name, err)
+		}
+	}
+
+	return sm, nil
+}
+
+// transitionHandlerKey is the key handlers passed to LoadStateMachineJSON
+// are expected to be registered under.
+func transitionHandlerKey(transitionType TransitionType, ruleName string) string {
+	return fmt.Sprintf("%s/%s", transitionType, ruleName)
+}
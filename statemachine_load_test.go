@@ -0,0 +1,235 @@
+package stateswitch
+
+import (
+	"encoding/json"
+	"testing"
+)
+
+type fakeStateSwitch struct {
+	state State
+}
+
+func (f *fakeStateSwitch) State() State { return f.state }
+
+func (f *fakeStateSwitch) SetState(state State) error {
+	f.state = state
+	return nil
+}
+
+func buildFixtureStateMachine(t *testing.T) StateMachine {
+	t.Helper()
+
+	sm := NewStateMachine()
+	sm.DescribeState(State("open"), StateDoc{Name: "Open"})
+	sm.DescribeState(State("closed"), StateDoc{Name: "Closed"})
+	sm.DescribeTransitionType(TransitionType("create"), TransitionTypeDoc{Name: "Create"})
+	sm.DescribeTransitionType(TransitionType("close"), TransitionTypeDoc{Name: "Close"})
+
+	if err := sm.AddTransition(TransitionRule{
+		TransitionType:   "create",
+		SourceStates:     StatesList{""},
+		DestinationState: "open",
+		Documentation:    TransitionRuleDoc{Name: "create"},
+	}); err != nil {
+		t.Fatalf("failed to add 'create' transition: %v", err)
+	}
+	if err := sm.AddTransition(TransitionRule{
+		TransitionType:   "close",
+		SourceStates:     StatesList{"open"},
+		DestinationState: "closed",
+		Documentation:    TransitionRuleDoc{Name: "close"},
+	}); err != nil {
+		t.Fatalf("failed to add 'close' transition: %v", err)
+	}
+
+	return sm
+}
+
+func TestLoadStateMachineJSONRoundTrip(t *testing.T) {
+	sm := buildFixtureStateMachine(t)
+
+	data, err := sm.AsJSON()
+	if err != nil {
+		t.Fatalf("failed to export fixture: %v", err)
+	}
+
+	var createRan, closeRan bool
+	handlers := map[string]TransitionHandler{
+		transitionHandlerKey("create", "create"): func(args TransitionArgs) error {
+			createRan = true
+			return nil
+		},
+		transitionHandlerKey("close", "close"): func(args TransitionArgs) error {
+			closeRan = true
+			return nil
+		},
+	}
+
+	loaded, err := LoadStateMachineJSON(data, handlers)
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+
+	sw := &fakeStateSwitch{state: ""}
+	if err := loaded.Run("create", sw, nil); err != nil {
+		t.Fatalf("failed to run 'create': %v", err)
+	}
+	if !createRan || sw.State() != "open" {
+		t.Errorf("expected 'create' handler to run and state to become 'open', got ran=%v state=%q", createRan, sw.State())
+	}
+
+	if err := loaded.Run("close", sw, nil); err != nil {
+		t.Fatalf("failed to run 'close': %v", err)
+	}
+	if !closeRan || sw.State() != "closed" {
+		t.Errorf("expected 'close' handler to run and state to become 'closed', got ran=%v state=%q", closeRan, sw.State())
+	}
+}
+
+func TestLoadStateMachineJSONMissingHandler(t *testing.T) {
+	sm := buildFixtureStateMachine(t)
+
+	data, err := sm.AsJSON()
+	if err != nil {
+		t.Fatalf("failed to export fixture: %v", err)
+	}
+
+	if _, err := LoadStateMachineJSON(data, nil); err == nil {
+		t.Fatal("expected an error when no handlers are registered, got nil")
+	}
+}
+
+func TestLoadStateMachineJSONPreservesLiteralInitialStateAmongMultipleSources(t *testing.T) {
+	doc := StateMachineJSON{
+		FormatVersion: CurrentFormatVersion,
+		States: map[string]StateJSON{
+			"initial": {Name: "Initial"},
+			"open":    {Name: "Open"},
+			"closed":  {Name: "Closed"},
+		},
+		TransitionTypes: map[string]TransitionTypeJSON{
+			"reopen": {Name: "Reopen"},
+		},
+		TransitionRules: []TransitionRuleJSON{
+			{TransitionType: "reopen", Name: "reopen", SourceStates: []string{"initial", "closed"}, DestinationState: "open"},
+		},
+	}
+
+	data, err := json.Marshal(doc)
+	if err != nil {
+		t.Fatalf("failed to marshal fixture: %v", err)
+	}
+
+	handlers := map[string]TransitionHandler{
+		transitionHandlerKey("reopen", "reopen"): func(args TransitionArgs) error { return nil },
+	}
+
+	loaded, err := LoadStateMachineJSON(data, handlers)
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+
+	sw := &fakeStateSwitch{state: "initial"}
+	if err := loaded.Run("reopen", sw, nil); err != nil {
+		t.Fatalf("expected literal 'initial' source state to be preserved (not remapped to \"\"), got error: %v", err)
+	}
+}
+
+func TestLoadStateMachineJSONWithWarningsSurfacesSinkStates(t *testing.T) {
+	sm := buildFixtureStateMachine(t)
+
+	data, err := sm.AsJSON()
+	if err != nil {
+		t.Fatalf("failed to export fixture: %v", err)
+	}
+
+	handlers := map[string]TransitionHandler{
+		transitionHandlerKey("create", "create"): func(args TransitionArgs) error { return nil },
+		transitionHandlerKey("close", "close"):   func(args TransitionArgs) error { return nil },
+	}
+
+	loaded, warnings, err := LoadStateMachineJSONWithWarnings(data, handlers)
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	if loaded == nil {
+		t.Fatal("expected a non-nil StateMachine")
+	}
+
+	var sawSink bool
+	for _, w := range warnings {
+		if w.State == "closed" {
+			sawSink = true
+		}
+	}
+	if !sawSink {
+		t.Errorf("expected a warning about 'closed' having no outgoing rules, got %v", warnings)
+	}
+}
+
+func validDocFixture() StateMachineJSON {
+	return StateMachineJSON{
+		FormatVersion: CurrentFormatVersion,
+		States: map[string]StateJSON{
+			"initial": {Name: "Initial"},
+			"open":    {Name: "Open"},
+			"closed":  {Name: "Closed"},
+		},
+		TransitionTypes: map[string]TransitionTypeJSON{
+			"create": {Name: "Create"},
+			"close":  {Name: "Close"},
+		},
+		TransitionRules: []TransitionRuleJSON{
+			{TransitionType: "create", Name: "create", SourceStates: []string{"initial"}, DestinationState: "open"},
+			{TransitionType: "close", Name: "close", SourceStates: []string{"open"}, DestinationState: "closed"},
+		},
+	}
+}
+
+func TestValidateAcceptsConsistentDocument(t *testing.T) {
+	if err := validDocFixture().Validate(); err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+}
+
+func TestValidateRejectsUnknownSourceState(t *testing.T) {
+	doc := validDocFixture()
+	doc.TransitionRules[0].SourceStates = []string{"nonexistent"}
+
+	if err := doc.Validate(); err == nil {
+		t.Fatal("expected an error for an unknown source state, got nil")
+	}
+}
+
+func TestValidateRejectsUnknownTransitionType(t *testing.T) {
+	doc := validDocFixture()
+	doc.TransitionRules[0].TransitionType = "nonexistent"
+
+	if err := doc.Validate(); err == nil {
+		t.Fatal("expected an error for an unknown transition type, got nil")
+	}
+}
+
+func TestWarningsFlagsUnreachableAndSinkStates(t *testing.T) {
+	doc := validDocFixture()
+	doc.States["orphan"] = StateJSON{Name: "Orphan"}
+
+	warnings := doc.Warnings()
+
+	var sawUnreachable, sawSink bool
+	for _, w := range warnings {
+		if w.State == "orphan" {
+			sawUnreachable = true
+		}
+		if w.State == "closed" {
+			sawSink = true
+		}
+	}
+
+	if !sawUnreachable {
+		t.Error("expected a warning about the unreachable 'orphan' state")
+	}
+	if !sawSink {
+		t.Error("expected a warning about 'closed' having no outgoing rules")
+	}
+}
@@ -0,0 +1,80 @@
+package stateswitch
+
+import (
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// CurrentFormatVersion is the schema version written to the
+// StateMachineJSON.FormatVersion field by Export/AsJSON. Bump the major
+// component whenever a field is renamed or removed in a way that breaks
+// existing consumers; bump the minor component for additive,
+// backward-compatible changes.
+const CurrentFormatVersion = "1.0"
+
+// LibraryVersion is the stateswitch module's own semver, written to the
+// StateMachineJSON.LibraryVersion field by Export/AsJSON. Unlike
+// CurrentFormatVersion, it is informational only - ParseStateMachineJSON
+// never checks it - but it lets consumers report which release of the
+// library produced a given document, the way Terraform's jsonstate embeds
+// terraform_version alongside its own format_version.
+const LibraryVersion = "0.1.0"
+
+// FormatVersionError is returned by ParseStateMachineJSON when a document's
+// FormatVersion is not compatible with CurrentFormatVersion.
+type FormatVersionError struct {
+	Got  string
+	Want string
+}
+
+func (e *FormatVersionError) Error() string {
+	return fmt.Sprintf("state machine JSON format_version %q is not compatible with %q understood by this version of stateswitch", e.Got, e.Want)
+}
+
+// ParseStateMachineJSON parses a document previously produced by
+// AsJSON/Export and checks that its FormatVersion is compatible (same major
+// version) with CurrentFormatVersion. The document is always returned, even
+// when the version check fails, so callers can inspect it or proceed at
+// their own risk; a *FormatVersionError signals the mismatch.
+func ParseStateMachineJSON(data []byte) (StateMachineJSON, error) {
+	var doc StateMachineJSON
+	if err := json.Unmarshal(data, &doc); err != nil {
+		return StateMachineJSON{}, fmt.Errorf("failed to unmarshal state machine JSON: %w", err)
+	}
+
+	if err := checkFormatVersion(doc.FormatVersion); err != nil {
+		return doc, err
+	}
+
+	return doc, nil
+}
+
+func checkFormatVersion(got string) error {
+	gotMajor, err := majorVersion(got)
+	if err != nil {
+		return fmt.Errorf("invalid format_version %q: %w", got, err)
+	}
+
+	wantMajor, err := majorVersion(CurrentFormatVersion)
+	if err != nil {
+		return err
+	}
+
+	if gotMajor != wantMajor {
+		return &FormatVersionError{Got: got, Want: CurrentFormatVersion}
+	}
+
+	return nil
+}
+
+func majorVersion(version string) (int, error) {
+	major := strings.SplitN(version, ".", 2)[0]
+	n, err := strconv.Atoi(major)
+	if err != nil {
+		return 0, fmt.Errorf("could not parse major component of version %q", version)
+	}
+
+	return n, nil
+}
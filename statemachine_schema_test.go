@@ -0,0 +1,79 @@
+package stateswitch
+
+import (
+	"encoding/json"
+	"testing"
+)
+
+func TestParseStateMachineJSONRoundTrip(t *testing.T) {
+	doc := StateMachineJSON{
+		FormatVersion:  CurrentFormatVersion,
+		LibraryVersion: LibraryVersion,
+		States: map[string]StateJSON{
+			"initial": {Name: "Initial"},
+			"done":    {Name: "Done"},
+		},
+		TransitionTypes: map[string]TransitionTypeJSON{
+			"finish": {Name: "Finish"},
+		},
+		TransitionRules: []TransitionRuleJSON{
+			{TransitionType: "finish", SourceStates: []string{"initial"}, DestinationState: "done"},
+		},
+	}
+
+	data, err := json.Marshal(doc)
+	if err != nil {
+		t.Fatalf("failed to marshal fixture: %v", err)
+	}
+
+	parsed, err := ParseStateMachineJSON(data)
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+
+	if parsed.FormatVersion != CurrentFormatVersion {
+		t.Errorf("expected format version %q, got %q", CurrentFormatVersion, parsed.FormatVersion)
+	}
+	if parsed.LibraryVersion != LibraryVersion {
+		t.Errorf("expected library version %q, got %q", LibraryVersion, parsed.LibraryVersion)
+	}
+	if len(parsed.States) != len(doc.States) {
+		t.Errorf("expected %d states, got %d", len(doc.States), len(parsed.States))
+	}
+}
+
+func TestParseStateMachineJSONVersionMismatch(t *testing.T) {
+	tests := []struct {
+		name          string
+		formatVersion string
+		wantErr       bool
+	}{
+		{name: "same major, newer minor is forward compatible", formatVersion: "1.9", wantErr: false},
+		{name: "older major is incompatible", formatVersion: "0.1", wantErr: true},
+		{name: "newer major is incompatible", formatVersion: "2.0", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			doc := StateMachineJSON{FormatVersion: tt.formatVersion}
+			data, err := json.Marshal(doc)
+			if err != nil {
+				t.Fatalf("failed to marshal fixture: %v", err)
+			}
+
+			_, err = ParseStateMachineJSON(data)
+			if tt.wantErr && err == nil {
+				t.Fatalf("expected an error for format_version %q, got nil", tt.formatVersion)
+			}
+			if !tt.wantErr && err != nil {
+				t.Fatalf("expected no error for format_version %q, got %v", tt.formatVersion, err)
+			}
+
+			if tt.wantErr {
+				if _, ok := err.(*FormatVersionError); !ok {
+					t.Errorf("expected *FormatVersionError, got %T", err)
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,34 @@
+package stateswitch
+
+import "time"
+
+// Serial returns the state machine's monotonic revision counter. It
+// increments every time AddTransition, DescribeState, or
+// DescribeTransitionType mutates the definition, so tooling can tell
+// whether a previously exported StateMachineJSON document is stale relative
+// to the running state machine.
+func (sm *stateMachine) Serial() int64 {
+	return sm.serial
+}
+
+// bumpSerial records a mutation to the state machine's definition.
+func (sm *stateMachine) bumpSerial() {
+	sm.serial++
+	sm.generatedAt = time.Now()
+}
+
+// SerialAt reports whether the state machine's definition is unchanged
+// since snapshot was generated, i.e. whether snapshot is still up to date.
+func (sm *stateMachine) SerialAt(snapshot StateMachineJSON) bool {
+	return sm.serial == snapshot.Serial
+}
+
+// DiffSince reports what changed in the state machine's definition since
+// prev was generated, by comparing prev against a fresh Export(). It is a
+// thin wrapper around Diff intended for the common "is my committed JSON
+// stale" check: generate prev once, keep it around (e.g. committed to
+// disk), and call DiffSince later to get a structural diff instead of just
+// a boolean.
+func (sm *stateMachine) DiffSince(prev StateMachineJSON) (StateMachineDiff, error) {
+	return Diff(prev, sm.Export()), nil
+}
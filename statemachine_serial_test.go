@@ -0,0 +1,69 @@
+package stateswitch
+
+import "testing"
+
+func TestSerialIncrementsOnAddTransition(t *testing.T) {
+	sm := NewStateMachine()
+	before := sm.Serial()
+
+	err := sm.AddTransition(TransitionRule{
+		TransitionType:   "create",
+		SourceStates:     StatesList{""},
+		DestinationState: "open",
+	})
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+
+	if sm.Serial() <= before {
+		t.Errorf("expected Serial() to increment after AddTransition, stayed at %d", sm.Serial())
+	}
+}
+
+func TestSerialAtAndDiffSinceReflectAddTransition(t *testing.T) {
+	sm := NewStateMachine()
+	snapshot := sm.Export()
+
+	if !sm.SerialAt(snapshot) {
+		t.Fatal("expected SerialAt to be true for a snapshot taken before any mutation")
+	}
+
+	if err := sm.AddTransition(TransitionRule{
+		TransitionType:   "create",
+		SourceStates:     StatesList{""},
+		DestinationState: "open",
+	}); err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+
+	if sm.SerialAt(snapshot) {
+		t.Fatal("expected SerialAt to be false after AddTransition changed the definition")
+	}
+
+	diff, err := sm.DiffSince(snapshot)
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	if diff.Empty() {
+		t.Fatal("expected DiffSince to report the newly added rule")
+	}
+	if len(diff.AddedRules) != 1 || diff.AddedRules[0].TransitionType != "create" {
+		t.Errorf("expected one added 'create' rule, got %v", diff.AddedRules)
+	}
+}
+
+func TestSerialIncrementsOnDescribeStateAndTransitionType(t *testing.T) {
+	sm := NewStateMachine()
+	afterInit := sm.Serial()
+
+	sm.DescribeState(State("open"), StateDoc{Name: "Open"})
+	afterState := sm.Serial()
+	if afterState <= afterInit {
+		t.Errorf("expected Serial() to increment after DescribeState, stayed at %d", afterState)
+	}
+
+	sm.DescribeTransitionType(TransitionType("create"), TransitionTypeDoc{Name: "Create"})
+	if sm.Serial() <= afterState {
+		t.Errorf("expected Serial() to increment after DescribeTransitionType, stayed at %d", sm.Serial())
+	}
+}